@@ -0,0 +1,78 @@
+// Package config holds the configuration that is compiled into a Ginkgo
+// suite binary. The `ginkgo` CLI forwards the relevant pieces of its own
+// flags through to the suite binary as -ginkgo.* flags; RunSpecs reads the
+// resulting GinkgoConfig to decide what to do before/after running specs.
+package config
+
+import (
+	"flag"
+	"time"
+)
+
+// GinkgoConfigType is populated from -ginkgo.* flags by Flags and consulted
+// by RunSpecs.
+type GinkgoConfigType struct {
+	// Per-suite pprof profile destinations. RunSpecs starts/stops the
+	// corresponding profiler around spec execution when these are set; the
+	// ginkgo CLI then merges the per-suite files across a recursive run.
+	CPUProfile   string
+	MemProfile   string
+	BlockProfile string
+	MutexProfile string
+
+	Trace string
+
+	// Timeout bounds how long a single spec may run before RunSpecs
+	// considers it hung.
+	Timeout time.Duration
+
+	// ProfileLabels controls whether each leaf node's execution is wrapped
+	// in pprof.Do so that CPU/block/mutex samples are tagged with the spec
+	// that produced them. Defaults to true when a profiler is enabled.
+	ProfileLabels bool
+
+	// Sampling rates applied before profiling starts. Zero means "leave the
+	// runtime default alone", except that a requested block or mutex profile
+	// then samples every event (rate/fraction 1).
+	CPUProfileRate       int
+	MemProfileRate       int
+	BlockProfileRate     int
+	MutexProfileFraction int
+
+	// GoroutineProfile, if set, is the path to write a goroutine profile to
+	// at suite end.
+	GoroutineProfile string
+
+	// DumpGoroutinesOnTimeout, if set, dumps a labeled goroutine profile and
+	// a human-readable stack dump alongside GoroutineProfile whenever a spec
+	// exceeds its timeout or the suite is interrupted.
+	DumpGoroutinesOnTimeout bool
+}
+
+// GinkgoConfig is the configuration for the currently executing suite
+// binary.
+var GinkgoConfig = GinkgoConfigType{}
+
+// The suite binary's testing main parses flag.CommandLine before any spec
+// runs, so the -ginkgo.* flags must be registered there too.
+func init() {
+	Flags(flag.CommandLine, &GinkgoConfig)
+}
+
+// Flags binds GinkgoConfig's fields onto fs so RunSpecs can pick up the
+// flags that the ginkgo CLI forwards into the compiled suite binary.
+func Flags(fs *flag.FlagSet, config *GinkgoConfigType) {
+	fs.StringVar(&config.CPUProfile, "ginkgo.cpuprofile", "", "If set, write a CPU profile to this file for the duration of the suite.")
+	fs.StringVar(&config.MemProfile, "ginkgo.memprofile", "", "If set, write a memory profile to this file at suite end.")
+	fs.StringVar(&config.BlockProfile, "ginkgo.blockprofile", "", "If set, write a block profile to this file at suite end.")
+	fs.StringVar(&config.MutexProfile, "ginkgo.mutexprofile", "", "If set, write a mutex profile to this file at suite end.")
+	fs.StringVar(&config.Trace, "ginkgo.trace", "", "If set, write a runtime/trace execution trace to this file for the duration of the suite.")
+	fs.DurationVar(&config.Timeout, "ginkgo.timeout", 0, "If set, a spec that runs longer than this is considered hung.")
+	fs.BoolVar(&config.ProfileLabels, "ginkgo.profile-labels", true, "If set, tag CPU/block/mutex profile samples with the spec, file, and node type that produced them.")
+	fs.IntVar(&config.CPUProfileRate, "ginkgo.cpuprofilerate", 0, "If set, passed to runtime.SetCPUProfileRate before CPU profiling starts.")
+	fs.IntVar(&config.MemProfileRate, "ginkgo.memprofilerate", 0, "If set, assigned to runtime.MemProfileRate before memory profiling starts.")
+	fs.IntVar(&config.BlockProfileRate, "ginkgo.blockprofilerate", 0, "If set, passed to runtime.SetBlockProfileRate before block profiling starts.")
+	fs.IntVar(&config.MutexProfileFraction, "ginkgo.mutexprofilefraction", 0, "If set, passed to runtime.SetMutexProfileFraction before mutex profiling starts.")
+	fs.StringVar(&config.GoroutineProfile, "ginkgo.goroutineprofile", "", "If set, write a goroutine profile to this file at suite end.")
+	fs.BoolVar(&config.DumpGoroutinesOnTimeout, "ginkgo.dump-goroutines-on-timeout", false, "If set, dump a labeled goroutine profile and a stack dump when a spec times out or the suite is interrupted.")
+}
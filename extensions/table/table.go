@@ -0,0 +1,51 @@
+// Package table provides DescribeTable, a way to register the same spec
+// body against a list of parameterized Entry values, each of which becomes
+// its own It.
+package table
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/onsi/ginkgo"
+)
+
+// TableEntry is a single row produced by Entry; its Parameters are passed to
+// the DescribeTable body function.
+type TableEntry struct {
+	Description string
+	Parameters  []interface{}
+}
+
+// Entry constructs a TableEntry with the given description and parameters.
+func Entry(description string, parameters ...interface{}) TableEntry {
+	return TableEntry{Description: description, Parameters: parameters}
+}
+
+// DescribeTable registers one It per entry, each of which calls body with
+// that entry's parameters via reflection.
+func DescribeTable(description string, body interface{}, entries ...TableEntry) bool {
+	bodyValue := reflect.ValueOf(body)
+
+	return ginkgo.Describe(description, func() {
+		for _, entry := range entries {
+			entry := entry
+			ginkgo.It(entry.Description, func() {
+				bodyType := bodyValue.Type()
+				if len(entry.Parameters) != bodyType.NumIn() && !bodyType.IsVariadic() {
+					panic(fmt.Sprintf("DescribeTable entry %q passes %d parameters but body takes %d", entry.Description, len(entry.Parameters), bodyType.NumIn()))
+				}
+
+				args := make([]reflect.Value, len(entry.Parameters))
+				for i, p := range entry.Parameters {
+					if p == nil {
+						args[i] = reflect.Zero(bodyType.In(i))
+						continue
+					}
+					args[i] = reflect.ValueOf(p)
+				}
+				bodyValue.Call(args)
+			})
+		}
+	})
+}
@@ -0,0 +1,47 @@
+// Package profiles merges the per-suite pprof profiles produced by a
+// recursive `ginkgo -r` run into a single composite profile, mirroring the
+// way combined_coverage merges per-suite coverprofiles.
+package profiles
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/google/pprof/profile"
+)
+
+// Merge deserializes the pprof profiles at paths and compacts them into a
+// single profile via profile.Merge, writing the result to outPath. It
+// returns an error if no profiles were found.
+func Merge(paths []string, outPath string) error {
+	if len(paths) == 0 {
+		return fmt.Errorf("no profiles to merge into %s", outPath)
+	}
+
+	parsed := make([]*profile.Profile, 0, len(paths))
+	for _, path := range paths {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		p, err := profile.Parse(f)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("failed to parse profile %s: %w", path, err)
+		}
+		parsed = append(parsed, p)
+	}
+
+	merged, err := profile.Merge(parsed)
+	if err != nil {
+		return fmt.Errorf("failed to merge profiles into %s: %w", outPath, err)
+	}
+
+	out, err := os.Create(outPath)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	return merged.Write(out)
+}
@@ -0,0 +1,46 @@
+// Command ginkgo builds and runs Ginkgo suites. This file implements just
+// enough of the real `ginkgo` CLI to exercise the run command: discover
+// suite directories, build+run each one's compiled test binary (forwarding
+// the relevant flags in as -ginkgo.* suite flags), and - when running
+// multiple suites with -r - merge their per-suite profiles together.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+)
+
+func main() {
+	os.Exit(runMain(os.Args[1:]))
+}
+
+func runMain(args []string) int {
+	fs := flag.NewFlagSet("ginkgo", flag.ContinueOnError)
+	flags := RegisterRunCommandFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return 1
+	}
+
+	suiteDirs, err := discoverSuiteDirs(".", flags.Recurse)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	for _, dir := range suiteDirs {
+		if err := buildAndRunSuite(dir, flags); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	}
+
+	if len(suiteDirs) > 1 {
+		if err := flags.CombineProfiles(suiteDirs); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/onsi/ginkgo/ginkgo/internal/profiles"
+)
+
+// RunCommandFlags holds the flags recognized by `ginkgo` (as opposed to
+// `ginkgo.<flag>`, which are forwarded into the compiled suite binary).
+type RunCommandFlags struct {
+	Recurse   bool
+	Nodes     int
+	OutputDir string
+
+	Cover                     bool
+	CoverProfile              string
+	KeepSeparateCoverprofiles bool
+
+	CPUProfile           string
+	MemProfile           string
+	BlockProfile         string
+	MutexProfile         string
+	KeepSeparateProfiles bool
+	ProfileLabels        bool
+
+	CPUProfileRate       int
+	MemProfileRate       int
+	BlockProfileRate     int
+	MutexProfileFraction int
+
+	GoroutineProfile        string
+	DumpGoroutinesOnTimeout bool
+
+	Trace   string
+	Timeout string
+}
+
+// profileFlag pairs a requested profile's CLI filename with the -ginkgo.*
+// suite flag used to forward it into the compiled suite binary.
+type profileFlag struct {
+	suiteFlag string
+	userFile  string
+}
+
+// RegisterRunCommandFlags binds the run command's flags onto fs.
+func RegisterRunCommandFlags(fs *flag.FlagSet) *RunCommandFlags {
+	flags := &RunCommandFlags{}
+
+	fs.BoolVar(&flags.Recurse, "r", false, "Recursively run all suites found under the current directory.")
+	fs.IntVar(&flags.Nodes, "nodes", 1, "Number of parallel test nodes to run.")
+	fs.StringVar(&flags.OutputDir, "output-dir", "", "If set, write the merged profiles of a recursive run into this directory (created if missing). Per-suite profiles, coverage output and suite binaries stay in each suite's own directory.")
+	fs.Bool("no-color", false, "Disable colored output. Output is never colored, so this flag has no effect.")
+
+	fs.BoolVar(&flags.Cover, "cover", false, "Run suites with coverage analysis enabled.")
+	fs.StringVar(&flags.CoverProfile, "coverprofile", "coverprofile.out", "Name of the file in which to store coverage results. It is not forwarded to the suite binaries, so no coverage file is written.")
+	fs.BoolVar(&flags.KeepSeparateCoverprofiles, "keep-separate-coverprofiles", false, "Accepted for compatibility. Coverprofiles are never merged across suites, so this flag has no effect.")
+
+	fs.StringVar(&flags.CPUProfile, "cpuprofile", "", "Name of the file in which to store the CPU profile.")
+	fs.StringVar(&flags.MemProfile, "memprofile", "", "Name of the file in which to store the memory profile.")
+	fs.StringVar(&flags.BlockProfile, "blockprofile", "", "Name of the file in which to store the block profile.")
+	fs.StringVar(&flags.MutexProfile, "mutexprofile", "", "Name of the file in which to store the mutex profile.")
+	fs.BoolVar(&flags.KeepSeparateProfiles, "keep-separate-profiles", false, "If set, do not merge cpu/mem/block/mutex profiles across suites; keep one set of profiles per suite.")
+	fs.BoolVar(&flags.ProfileLabels, "profile-labels", true, "If set, tag CPU/block/mutex profile samples with the spec, file, and node type that produced them.")
+
+	fs.StringVar(&flags.Trace, "trace", "", "Name of the file in which to store a runtime/trace execution trace.")
+	fs.StringVar(&flags.Timeout, "timeout", "", "If set, forwarded to each suite binary as the per-spec timeout (e.g. \"1s\"); a spec that runs longer is reported as hung.")
+
+	fs.IntVar(&flags.CPUProfileRate, "cpuprofilerate", 0, "If set, forwarded to runtime.SetCPUProfileRate before CPU profiling starts.")
+	fs.IntVar(&flags.MemProfileRate, "memprofilerate", 0, "If set, forwarded to runtime.MemProfileRate before memory profiling starts.")
+	fs.IntVar(&flags.BlockProfileRate, "blockprofilerate", 0, "If set, forwarded to runtime.SetBlockProfileRate before block profiling starts.")
+	fs.IntVar(&flags.MutexProfileFraction, "mutexprofilefraction", 0, "If set, forwarded to runtime.SetMutexProfileFraction before mutex profiling starts.")
+
+	fs.StringVar(&flags.GoroutineProfile, "goroutineprofile", "", "Name of the file in which to store a goroutine profile, written at suite end.")
+	fs.BoolVar(&flags.DumpGoroutinesOnTimeout, "dump-goroutines-on-timeout", false, "If set, dump a labeled goroutine profile and a stack dump when a spec times out or the suite is interrupted.")
+
+	return flags
+}
+
+// TracePathForNode computes the -ginkgo.trace path that should be forwarded
+// to a given parallel node's suite binary. Execution traces, unlike pprof
+// profiles, cannot be trivially merged, so each node gets its own file:
+// trace.out when running on a single node, trace.<node>.out (1-indexed)
+// when running with nodes > 1.
+func (flags *RunCommandFlags) TracePathForNode(node int) string {
+	if flags.Trace == "" {
+		return ""
+	}
+	if flags.Nodes <= 1 {
+		return flags.Trace
+	}
+
+	ext := filepath.Ext(flags.Trace)
+	base := flags.Trace[:len(flags.Trace)-len(ext)]
+	return fmt.Sprintf("%s.%d%s", base, node, ext)
+}
+
+// profileFlags returns a profileFlag for each profile kind that was
+// requested, pairing the user-facing filename with the -ginkgo.* suite flag
+// used to forward it into the compiled suite binary.
+func (flags *RunCommandFlags) profileFlags() []profileFlag {
+	var pfs []profileFlag
+	for _, pf := range []profileFlag{
+		{suiteFlag: "ginkgo.cpuprofile", userFile: flags.CPUProfile},
+		{suiteFlag: "ginkgo.memprofile", userFile: flags.MemProfile},
+		{suiteFlag: "ginkgo.blockprofile", userFile: flags.BlockProfile},
+		{suiteFlag: "ginkgo.mutexprofile", userFile: flags.MutexProfile},
+	} {
+		if pf.userFile != "" {
+			pfs = append(pfs, pf)
+		}
+	}
+	return pfs
+}
+
+// CombineProfiles is invoked after a recursive (`-r`) run completes. For
+// each profile kind that was requested it either merges the per-suite
+// profiles produced in suiteDirs into a single composite profile (the
+// default), or, when KeepSeparateProfiles is set, leaves them where each
+// suite wrote them. The per-suite profiles are left in place in both cases.
+func (flags *RunCommandFlags) CombineProfiles(suiteDirs []string) error {
+	if flags.KeepSeparateProfiles || len(suiteDirs) <= 1 {
+		return nil
+	}
+
+	destDir := flags.OutputDir
+	if destDir == "" && len(suiteDirs) > 0 {
+		destDir = filepath.Dir(suiteDirs[0])
+	}
+	if err := os.MkdirAll(destDir, 0755); err != nil {
+		return err
+	}
+
+	for _, pf := range flags.profileFlags() {
+		paths := make([]string, 0, len(suiteDirs))
+		for _, dir := range suiteDirs {
+			paths = append(paths, filepath.Join(dir, pf.userFile))
+		}
+
+		outPath := filepath.Join(destDir, pf.userFile)
+		if err := profiles.Merge(paths, outPath); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
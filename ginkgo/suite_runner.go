@@ -0,0 +1,161 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/onsi/ginkgo/ginkgo/internal/profiles"
+)
+
+// discoverSuiteDirs returns the directories under root that contain a Go
+// test suite. When recurse is false only root itself is considered.
+func discoverSuiteDirs(root string, recurse bool) ([]string, error) {
+	if !recurse {
+		return []string{root}, nil
+	}
+
+	var dirs []string
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			return nil
+		}
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			if !entry.IsDir() && filepath.Ext(entry.Name()) == ".go" {
+				dirs = append(dirs, path)
+				break
+			}
+		}
+		return nil
+	})
+	return dirs, err
+}
+
+// buildAndRunSuite compiles the suite in dir to a <pkg>.test binary and runs
+// it once per node, forwarding flags as -ginkgo.* suite flags. When running
+// with more than one node, per-node profile fragments are merged into the
+// single per-suite profile file the rest of the pipeline (CombineProfiles)
+// expects to find.
+func buildAndRunSuite(dir string, flags *RunCommandFlags) error {
+	// runSuiteNode runs the binary with dir as its working directory, which
+	// is also where a relative path would be resolved, so use an absolute one.
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	pkgName := filepath.Base(absDir)
+	binaryPath := filepath.Join(absDir, pkgName+".test")
+
+	buildArgs := []string{"test", "-c"}
+	if flags.Cover {
+		buildArgs = append(buildArgs, "-cover")
+	}
+	buildArgs = append(buildArgs, "-o", binaryPath, "./"+dir)
+
+	build := exec.Command("go", buildArgs...)
+	build.Stdout, build.Stderr = os.Stdout, os.Stderr
+	if err := build.Run(); err != nil {
+		return fmt.Errorf("failed to build suite in %s: %w", dir, err)
+	}
+
+	nodes := flags.Nodes
+	if nodes < 1 {
+		nodes = 1
+	}
+
+	for node := 1; node <= nodes; node++ {
+		if err := runSuiteNode(binaryPath, dir, node, flags); err != nil {
+			return err
+		}
+	}
+
+	if nodes > 1 {
+		if err := mergeNodeProfiles(dir, flags, nodes); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// runSuiteNode execs binaryPath once, with dir as its working directory,
+// forwarding flags translated into the -ginkgo.* flags the suite binary
+// understands.
+func runSuiteNode(binaryPath string, dir string, node int, flags *RunCommandFlags) error {
+	var args []string
+
+	for _, pf := range flags.profileFlags() {
+		args = append(args, "-"+pf.suiteFlag+"="+nodeProfilePath(pf.userFile, node, flags.Nodes))
+	}
+	if tracePath := flags.TracePathForNode(node); tracePath != "" {
+		args = append(args, "-ginkgo.trace="+tracePath)
+	}
+	args = append(args, fmt.Sprintf("-ginkgo.profile-labels=%t", flags.ProfileLabels))
+	if flags.CPUProfileRate != 0 {
+		args = append(args, fmt.Sprintf("-ginkgo.cpuprofilerate=%d", flags.CPUProfileRate))
+	}
+	if flags.MemProfileRate != 0 {
+		args = append(args, fmt.Sprintf("-ginkgo.memprofilerate=%d", flags.MemProfileRate))
+	}
+	if flags.BlockProfileRate != 0 {
+		args = append(args, fmt.Sprintf("-ginkgo.blockprofilerate=%d", flags.BlockProfileRate))
+	}
+	if flags.MutexProfileFraction != 0 {
+		args = append(args, fmt.Sprintf("-ginkgo.mutexprofilefraction=%d", flags.MutexProfileFraction))
+	}
+	if flags.GoroutineProfile != "" {
+		args = append(args, "-ginkgo.goroutineprofile="+flags.GoroutineProfile)
+	}
+	if flags.DumpGoroutinesOnTimeout {
+		args = append(args, "-ginkgo.dump-goroutines-on-timeout=true")
+	}
+	if flags.Timeout != "" {
+		args = append(args, "-ginkgo.timeout="+flags.Timeout)
+	}
+
+	cmd := exec.Command(binaryPath, args...)
+	cmd.Dir = dir
+	cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
+	return cmd.Run()
+}
+
+// nodeProfilePath returns the path a given node should write a profile
+// named userFile to. With a single node there's nothing to disambiguate, so
+// the node just writes userFile directly; with multiple nodes each gets a
+// distinct fragment that mergeNodeProfiles later compacts back into
+// userFile.
+func nodeProfilePath(userFile string, node int, nodes int) string {
+	if nodes <= 1 {
+		return userFile
+	}
+	return fmt.Sprintf("%s.node%d", userFile, node)
+}
+
+// mergeNodeProfiles compacts the per-node profile fragments written by
+// runSuiteNode back into the single per-suite profile file the rest of the
+// pipeline expects, removing the fragments afterwards.
+func mergeNodeProfiles(dir string, flags *RunCommandFlags, nodes int) error {
+	for _, pf := range flags.profileFlags() {
+		fragments := make([]string, 0, nodes)
+		for node := 1; node <= nodes; node++ {
+			fragments = append(fragments, filepath.Join(dir, nodeProfilePath(pf.userFile, node, nodes)))
+		}
+
+		outPath := filepath.Join(dir, pf.userFile)
+		if err := profiles.Merge(fragments, outPath); err != nil {
+			return err
+		}
+		for _, fragment := range fragments {
+			os.Remove(fragment)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package ginkgo
+
+import (
+	"os"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"strings"
+	"syscall"
+)
+
+// writeGoroutineProfile writes the labeled goroutine profile to path, as
+// requested via --goroutineprofile.
+func writeGoroutineProfile(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return pprof.Lookup("goroutine").WriteTo(f, 0)
+}
+
+// dumpGoroutines writes both a labeled goroutine profile (profilePath) and a
+// human-readable stack dump (stackPath) of every goroutine currently
+// running, for post-mortem diagnosis of a hung spec or an interrupted suite.
+func dumpGoroutines(profilePath string, stackPath string) error {
+	if err := writeGoroutineProfile(profilePath); err != nil {
+		return err
+	}
+
+	buf := make([]byte, 1<<20)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+
+	return os.WriteFile(stackPath, buf, 0644)
+}
+
+// stackDumpPath derives the human-readable stack dump path that accompanies
+// a goroutine profile written to profilePath, e.g. "goroutines.out" ->
+// "goroutines.txt".
+func stackDumpPath(profilePath string) string {
+	ext := filepath.Ext(profilePath)
+	return strings.TrimSuffix(profilePath, ext) + ".txt"
+}
+
+// installDumpGoroutinesOnTimeoutHandler arranges for dumpGoroutines to run,
+// writing profilePath/stackPath, the first time the suite is sent SIGQUIT
+// (e.g. via `kill -QUIT`) or notifyTimeout is closed because a spec exceeded
+// --timeout; later events produce no further dump. It returns a function
+// that stops watching for these events.
+func installDumpGoroutinesOnTimeoutHandler(profilePath string, stackPath string, notifyTimeout <-chan struct{}) (stop func()) {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGQUIT)
+	done := make(chan struct{})
+
+	go func() {
+		select {
+		case <-sigCh:
+			dumpGoroutines(profilePath, stackPath)
+		case <-notifyTimeout:
+			dumpGoroutines(profilePath, stackPath)
+		case <-done:
+		}
+	}()
+
+	return func() {
+		signal.Stop(sigCh)
+		close(done)
+	}
+}
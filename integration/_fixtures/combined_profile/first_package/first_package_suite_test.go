@@ -0,0 +1,13 @@
+package first_package_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestFirstPackage(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "FirstPackage Suite")
+}
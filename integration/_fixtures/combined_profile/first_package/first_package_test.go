@@ -0,0 +1,14 @@
+package first_package_test
+
+import (
+	first_package "github.com/onsi/ginkgo/integration/_fixtures/combined_profile/first_package"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("FirstPackage", func() {
+	It("does something expensive", func() {
+		Ω(first_package.SomethingExpensive()).Should(BeNumerically(">", 0))
+	})
+})
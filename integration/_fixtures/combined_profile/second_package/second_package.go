@@ -0,0 +1,13 @@
+package second_package
+
+// SomethingElseExpensive burns CPU and allocates memory so it shows up near
+// the top of a cpu.out/mem.out profile.
+func SomethingElseExpensive() int {
+	sum := 0
+	data := make([]int, 0, 1024)
+	for i := 0; i < 2000000; i++ {
+		sum += i * i
+		data = append(data, i)
+	}
+	return sum + len(data)
+}
@@ -0,0 +1,13 @@
+package second_package_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestSecondPackage(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "SecondPackage Suite")
+}
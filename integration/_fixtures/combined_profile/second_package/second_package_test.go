@@ -0,0 +1,14 @@
+package second_package_test
+
+import (
+	second_package "github.com/onsi/ginkgo/integration/_fixtures/combined_profile/second_package"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SecondPackage", func() {
+	It("does something else expensive", func() {
+		Ω(second_package.SomethingElseExpensive()).Should(BeNumerically(">", 0))
+	})
+})
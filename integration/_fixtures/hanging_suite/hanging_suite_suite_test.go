@@ -0,0 +1,13 @@
+package hanging_suite_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestHangingSuite(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "HangingSuite Suite")
+}
@@ -0,0 +1,17 @@
+package hanging_suite_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+)
+
+var _ = Describe("HangingSuite", func() {
+	It("hangs forever", func() {
+		select {}
+	})
+
+	It("finishes quickly", func() {
+		time.Sleep(time.Millisecond)
+	})
+})
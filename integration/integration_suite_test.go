@@ -0,0 +1,136 @@
+package integration_test
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gexec"
+)
+
+func TestIntegration(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Integration Suite")
+}
+
+// fixtureManager mounts copies of integration/_fixtures/* into a scratch
+// directory so that tests can run the compiled ginkgo binary against them
+// without mutating the checked-in fixtures.
+type fixtureManager struct {
+	tmpDir string
+}
+
+var fm = &fixtureManager{}
+
+var pathToGinkgo string
+
+var _ = BeforeSuite(func() {
+	var err error
+	pathToGinkgo, err = gexec.Build("github.com/onsi/ginkgo/ginkgo")
+	Ω(err).ShouldNot(HaveOccurred())
+})
+
+var _ = AfterSuite(func() {
+	gexec.CleanupBuildArtifacts()
+})
+
+var _ = BeforeEach(func() {
+	tmpDir, err := ioutil.TempDir("", "ginkgo-integration")
+	Ω(err).ShouldNot(HaveOccurred())
+	fm.tmpDir = tmpDir
+})
+
+var _ = AfterEach(func() {
+	os.RemoveAll(fm.tmpDir)
+})
+
+// MountFixture copies integration/_fixtures/name into the current scratch
+// directory so it can be built and run in isolation.
+func (fm *fixtureManager) MountFixture(name string) {
+	src := filepath.Join("_fixtures", name)
+	dst := filepath.Join(fm.tmpDir, name)
+	Ω(copyDir(src, dst)).ShouldNot(HaveOccurred())
+}
+
+// PathTo joins the scratch-mounted fixture's directory with any additional
+// path components, e.g. fm.PathTo("profile", "slow_memory_hog/cpu.out").
+func (fm *fixtureManager) PathTo(fixture string, rest ...string) string {
+	parts := append([]string{fm.tmpDir, fixture}, rest...)
+	return filepath.Join(parts...)
+}
+
+// PackageNameFor returns the import path a mounted fixture can be referred
+// to by, e.g. for -coverpkg.
+func (fm *fixtureManager) PackageNameFor(fixture string) string {
+	return filepath.Join("github.com/onsi/ginkgo/integration/_fixtures", fixture)
+}
+
+// RemoveFile removes a file previously written into a mounted fixture,
+// e.g. a coverage or profile output from an earlier run in the same spec.
+func (fm *fixtureManager) RemoveFile(fixture string, rest ...string) {
+	Ω(os.Remove(fm.PathTo(fixture, rest...))).Should(Or(Succeed(), MatchError(ContainSubstring("no such file"))))
+}
+
+// ContentOf returns the contents of a file previously written into a
+// mounted fixture.
+func (fm *fixtureManager) ContentOf(fixture string, rest ...string) string {
+	content, err := ioutil.ReadFile(fm.PathTo(fixture, rest...))
+	Ω(err).ShouldNot(HaveOccurred())
+	return string(content)
+}
+
+// startGinkgo runs the compiled ginkgo binary with args, using dir as its
+// working directory, and returns the running session.
+func startGinkgo(dir string, args ...string) *gexec.Session {
+	cmd := exec.Command(pathToGinkgo, args...)
+	cmd.Dir = dir
+	session, err := gexec.Start(cmd, GinkgoWriter, GinkgoWriter)
+	Ω(err).ShouldNot(HaveOccurred())
+	return session
+}
+
+func copyDir(src string, dst string) error {
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dst, 0755); err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		srcPath := filepath.Join(src, entry.Name())
+		dstPath := filepath.Join(dst, entry.Name())
+		if entry.IsDir() {
+			if err := copyDir(srcPath, dstPath); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := copyFile(srcPath, dstPath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func copyFile(src string, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	_, err = io.Copy(out, in)
+	return err
+}
@@ -37,6 +37,41 @@ func (lines ProfileLines) FindCaller(caller string) ProfileLine {
 
 var PROFILE_RE = regexp.MustCompile(`[\d\.]+[MBms]*\s*[\d\.]+\%\s*[\d\.]+\%\s*([\d\.]+[MBnms]*)\s*[\d\.]+\%\s*(.*)`)
 
+var PROFILE_TAG_RE = regexp.MustCompile(`ginkgo\.spec:(.*)`)
+
+// PROFILE_TAG_VALUE_RE matches one value line under a tag key's header in
+// `go tool pprof -tags` output, e.g. "  120ms (  100%): some spec text".
+var PROFILE_TAG_VALUE_RE = regexp.MustCompile(`^\s*[\d\.]+[a-zA-Z]*\s*(?:\(\s*[\d\.]+%\))?:\s*(.*)$`)
+
+// ParseProfileTags shells out to `go tool pprof -tags` and returns the set of
+// ginkgo.spec sample labels found in the profile.
+func ParseProfileTags(binary string, path string) []string {
+	cmd := exec.Command("go", "tool", "pprof", "-tags", binary, path)
+	output, err := cmd.CombinedOutput()
+	GinkgoWriter.Printf("Profile tags for: %s\n%s\n", path, string(output))
+	ExpectWithOffset(1, err).ShouldNot(HaveOccurred())
+	out := []string{}
+	inSpecTags := false
+	for _, line := range strings.Split(string(output), "\n") {
+		// The "ginkgo.spec: Total ..." header is followed by one line per
+		// label value, up to the blank line that ends the key's section.
+		if PROFILE_TAG_RE.MatchString(line) {
+			inSpecTags = true
+			continue
+		}
+		if strings.TrimSpace(line) == "" {
+			inSpecTags = false
+			continue
+		}
+		matches := PROFILE_TAG_VALUE_RE.FindStringSubmatch(line)
+		if !inSpecTags || matches == nil {
+			continue
+		}
+		out = append(out, strings.TrimSpace(matches[1]))
+	}
+	return out
+}
+
 func ParseProfile(binary string, path string) ProfileLines {
 	cmd := exec.Command("go", "tool", "pprof", "-cum", "-top", binary, path)
 	output, err := cmd.CombinedOutput()
@@ -222,6 +257,11 @@ var _ = Describe("Profiling Specs", func() {
 				// Assrting on the amount of memory consumed should be stable across tests as the function always builds a large array of this size
 				Ω(memProfile.FindCaller("slow_memory_hog.SomethingExpensive").CumStat).Should(BeNumerically(">=", 200))
 
+				// --profile-labels is on by default under --cpuprofile, so every sample in cpu.out should
+				// carry a ginkgo.spec tag naming the spec that produced it
+				cpuProfileTags := ParseProfileTags(pathToBinary("slow_memory_hog"), pathToProfile("slow_memory_hog", "cpu.out"))
+				Ω(cpuProfileTags).Should(ContainElement(ContainSubstring("SomethingExpensive is slow")))
+
 				blockProfile := ParseProfile(pathToBinary("block_contest"), pathToProfile("block_contest", "block.out"))
 				// The BlockProfile for the block_contest test should list two channel-reading functions:
 				// block_contest.ReadTheChannel is called 10 times and takes ~5ms per call
@@ -262,5 +302,164 @@ var _ = Describe("Profiling Specs", func() {
 				"-nodes=3", "--output-dir=./profiles",
 			),
 		)
+
+		Context("when multiple suites are tested", func() {
+			BeforeEach(func() {
+				fm.MountFixture("combined_profile")
+			})
+
+			It("merges the per-suite profiles into a single composite profile", func() {
+				session := startGinkgo(fm.PathTo("combined_profile"), "--no-color", "-r", "-nodes=2",
+					"--cpuprofile=cpu.out", "--memprofile=mem.out", "--blockprofile=block.out", "--mutexprofile=mutex.out")
+				Eventually(session).Should(gexec.Exit(0))
+
+				for _, profile := range []string{"cpu.out", "mem.out", "block.out", "mutex.out"} {
+					Ω(fm.PathTo("combined_profile", profile)).Should(BeAnExistingFile())
+					Ω(fm.PathTo("combined_profile", "first_package/"+profile)).Should(BeAnExistingFile())
+					Ω(fm.PathTo("combined_profile", "second_package/"+profile)).Should(BeAnExistingFile())
+				}
+
+				By("ensuring the composite profile carries samples from both suites")
+				mergedCPUProfile := ParseProfile(fm.PathTo("combined_profile", "first_package/first_package.test"), fm.PathTo("combined_profile", "cpu.out"))
+				Ω(mergedCPUProfile.FindCaller("first_package.SomethingExpensive").Index).Should(BeNumerically("<=", 10))
+				Ω(mergedCPUProfile.FindCaller("second_package.SomethingElseExpensive").Index).Should(BeNumerically("<=", 10))
+			})
+
+			Context("when --keep-separate-profiles is set", func() {
+				It("generates separate per-suite profiles instead of merging them", func() {
+					session := startGinkgo(fm.PathTo("combined_profile"), "--no-color", "-r", "-nodes=2",
+						"--cpuprofile=cpu.out", "--memprofile=mem.out", "--blockprofile=block.out", "--mutexprofile=mutex.out",
+						"--keep-separate-profiles")
+					Eventually(session).Should(gexec.Exit(0))
+
+					for _, profile := range []string{"cpu.out", "mem.out", "block.out", "mutex.out"} {
+						Ω(fm.PathTo("combined_profile", profile)).ShouldNot(BeAnExistingFile())
+						Ω(fm.PathTo("combined_profile", "first_package/"+profile)).Should(BeAnExistingFile())
+						Ω(fm.PathTo("combined_profile", "second_package/"+profile)).Should(BeAnExistingFile())
+					}
+				})
+			})
+		})
+
+		Context("when --profile-labels=false is set", func() {
+			It("omits ginkgo.spec sample labels from the resulting profiles", func() {
+				session := startGinkgo(fm.PathTo("profile"), "--no-color", "-r", "--cpuprofile=cpu.out", "--profile-labels=false")
+				Eventually(session).Should(gexec.Exit(0))
+
+				cpuProfileTags := ParseProfileTags(fm.PathTo("profile", "slow_memory_hog/slow_memory_hog.test"), fm.PathTo("profile", "slow_memory_hog/cpu.out"))
+				Ω(cpuProfileTags).Should(BeEmpty())
+			})
+		})
+
+		Context("when custom sampling rates are configured", func() {
+			It("collects coarser block samples when --blockprofilerate is raised", func() {
+				defaultSession := startGinkgo(fm.PathTo("profile"), "--no-color", "-r", "--blockprofile=block.out")
+				Eventually(defaultSession).Should(gexec.Exit(0))
+				defaultBlockProfile := ParseProfile(fm.PathTo("profile", "block_contest/block_contest.test"), fm.PathTo("profile", "block_contest/block.out"))
+				fm.RemoveFile("profile", "block_contest/block.out")
+
+				coarseSession := startGinkgo(fm.PathTo("profile"), "--no-color", "-r", "--blockprofile=block.out", "--blockprofilerate=1000000")
+				Eventually(coarseSession).Should(gexec.Exit(0))
+				coarseBlockProfile := ParseProfile(fm.PathTo("profile", "block_contest/block_contest.test"), fm.PathTo("profile", "block_contest/block.out"))
+
+				Ω(len(coarseBlockProfile)).Should(BeNumerically("<=", len(defaultBlockProfile)))
+			})
+
+			It("collects fewer mutex samples when --mutexprofilefraction is raised", func() {
+				defaultSession := startGinkgo(fm.PathTo("profile"), "--no-color", "-r", "--mutexprofile=mutex.out")
+				Eventually(defaultSession).Should(gexec.Exit(0))
+				defaultMutexProfile := ParseProfile(fm.PathTo("profile", "lock_contest/lock_contest.test"), fm.PathTo("profile", "lock_contest/mutex.out"))
+				fm.RemoveFile("profile", "lock_contest/mutex.out")
+
+				coarseSession := startGinkgo(fm.PathTo("profile"), "--no-color", "-r", "--mutexprofile=mutex.out", "--mutexprofilefraction=1000000")
+				Eventually(coarseSession).Should(gexec.Exit(0))
+				coarseMutexProfile := ParseProfile(fm.PathTo("profile", "lock_contest/lock_contest.test"), fm.PathTo("profile", "lock_contest/mutex.out"))
+
+				Ω(len(coarseMutexProfile)).Should(BeNumerically("<=", len(defaultMutexProfile)))
+			})
+
+			It("accepts --cpuprofilerate and --memprofilerate without affecting suite success", func() {
+				session := startGinkgo(fm.PathTo("profile"), "--no-color", "-r", "--cpuprofile=cpu.out", "--memprofile=mem.out", "--cpuprofilerate=500", "--memprofilerate=2048")
+				Eventually(session).Should(gexec.Exit(0))
+
+				Ω(fm.PathTo("profile", "slow_memory_hog/cpu.out")).Should(BeAnExistingFile())
+				Ω(fm.PathTo("profile", "slow_memory_hog/mem.out")).Should(BeAnExistingFile())
+			})
+		})
+	})
+
+	Describe("measuring an execution trace", func() {
+		BeforeEach(func() {
+			fm.MountFixture("profile")
+		})
+
+		Context("when running a single node", func() {
+			It("writes a parseable trace file alongside the binary", func() {
+				session := startGinkgo(fm.PathTo("profile"), "--no-color", "-r", "--trace=trace.out")
+				Eventually(session).Should(gexec.Exit(0))
+
+				for _, pkg := range []string{"slow_memory_hog", "block_contest", "lock_contest"} {
+					tracePath := fm.PathTo("profile", pkg+"/trace.out")
+					Ω(tracePath).Should(BeAnExistingFile())
+
+					output, err := exec.Command("go", "tool", "trace", "-pprof=net", tracePath).CombinedOutput()
+					ExpectWithOffset(1, err).ShouldNot(HaveOccurred(), string(output))
+				}
+			})
+		})
+
+		Context("when running with multiple nodes", func() {
+			It("writes one trace file per node since traces cannot be merged", func() {
+				session := startGinkgo(fm.PathTo("profile"), "--no-color", "-r", "-nodes=3", "--trace=trace.out")
+				Eventually(session).Should(gexec.Exit(0))
+
+				for _, pkg := range []string{"slow_memory_hog", "block_contest", "lock_contest"} {
+					Ω(fm.PathTo("profile", pkg+"/trace.out")).ShouldNot(BeAnExistingFile())
+					for node := 1; node <= 3; node++ {
+						tracePath := fm.PathTo("profile", pkg+"/"+fmt.Sprintf("trace.%d.out", node))
+						Ω(tracePath).Should(BeAnExistingFile())
+
+						output, err := exec.Command("go", "tool", "trace", "-pprof=net", tracePath).CombinedOutput()
+						ExpectWithOffset(1, err).ShouldNot(HaveOccurred(), string(output))
+					}
+				}
+			})
+		})
+	})
+
+	Describe("measuring goroutine profiles and dumping goroutines on a hang", func() {
+		BeforeEach(func() {
+			fm.MountFixture("profile")
+		})
+
+		Context("with --goroutineprofile set", func() {
+			It("writes a goroutine profile at suite end", func() {
+				session := startGinkgo(fm.PathTo("profile"), "--no-color", "-r", "--goroutineprofile=goroutines.out")
+				Eventually(session).Should(gexec.Exit(0))
+
+				for _, pkg := range []string{"slow_memory_hog", "block_contest", "lock_contest"} {
+					Ω(fm.PathTo("profile", pkg+"/goroutines.out")).Should(BeAnExistingFile())
+				}
+			})
+		})
+
+		Context("with --dump-goroutines-on-timeout set", func() {
+			BeforeEach(func() {
+				fm.MountFixture("hanging_suite")
+			})
+
+			It("dumps a labeled goroutine profile and a human-readable stack dump when a spec hangs", func() {
+				session := startGinkgo(fm.PathTo("hanging_suite"), "--no-color", "--timeout=1s", "--dump-goroutines-on-timeout")
+				Eventually(session, "5s").Should(gexec.Exit(1))
+
+				Ω(fm.PathTo("hanging_suite", "goroutines.out")).Should(BeAnExistingFile())
+				Ω(fm.PathTo("hanging_suite", "goroutines.txt")).Should(BeAnExistingFile())
+
+				Ω(fm.ContentOf("hanging_suite", "goroutines.txt")).Should(ContainSubstring("goroutine"))
+
+				tags := ParseProfileTags(fm.PathTo("hanging_suite", "hanging_suite.test"), fm.PathTo("hanging_suite", "goroutines.out"))
+				Ω(tags).Should(ContainElement(ContainSubstring("hangs forever")))
+			})
+		})
 	})
 })
\ No newline at end of file
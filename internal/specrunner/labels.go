@@ -0,0 +1,26 @@
+// Package specrunner executes a suite's leaf nodes (It/BeforeEach/AfterEach
+// etc.) and instruments that execution for the profilers configured via
+// config.GinkgoConfig.
+package specrunner
+
+import (
+	"context"
+	"runtime/pprof"
+)
+
+// RunLeafNodeWithLabels runs run inside pprof.Do, tagging every CPU/block/
+// mutex sample it produces with the spec, file, and node type that produced
+// it. When labelsEnabled is false (--profile-labels=false) it just calls run
+// directly so there is no pprof.Do overhead.
+func RunLeafNodeWithLabels(ctx context.Context, labelsEnabled bool, specText string, codeLocation string, nodeType string, run func(context.Context)) {
+	if !labelsEnabled {
+		run(ctx)
+		return
+	}
+
+	pprof.Do(ctx, pprof.Labels(
+		"ginkgo.spec", specText,
+		"ginkgo.file", codeLocation,
+		"ginkgo.node", nodeType,
+	), run)
+}
@@ -0,0 +1,17 @@
+package ginkgo
+
+import (
+	"context"
+	"runtime/pprof"
+)
+
+// WithLabels returns a copy of ctx carrying the given key/value pairs as
+// pprof labels. It does not label the current goroutine itself: samples are
+// tagged only once the returned context is applied, e.g. via
+// pprof.SetGoroutineLabels or pprof.Do. It is a thin wrapper around
+// pprof.WithLabels/pprof.Labels for use inside specs,
+// complementing the ginkgo.spec/ginkgo.file/ginkgo.node labels RunSpecs
+// already attaches to each leaf node when --profile-labels is enabled.
+func WithLabels(ctx context.Context, labelKVs ...string) context.Context {
+	return pprof.WithLabels(ctx, pprof.Labels(labelKVs...))
+}
@@ -0,0 +1,32 @@
+package ginkgo
+
+import (
+	"runtime"
+
+	"github.com/onsi/ginkgo/config"
+)
+
+// applyProfileRates tunes the runtime's profiler sampling rates from the
+// -ginkgo.cpuprofilerate/-ginkgo.memprofilerate/-ginkgo.blockprofilerate/
+// -ginkgo.mutexprofilefraction flags. When a block or mutex profile is
+// requested without a rate, it samples every event (rate/fraction 1). It
+// must run before the corresponding profiler is started, since these rates
+// only affect samples taken after they're set.
+func applyProfileRates(conf config.GinkgoConfigType) {
+	if conf.CPUProfileRate != 0 {
+		runtime.SetCPUProfileRate(conf.CPUProfileRate)
+	}
+	if conf.MemProfileRate != 0 {
+		runtime.MemProfileRate = conf.MemProfileRate
+	}
+	if conf.BlockProfileRate != 0 {
+		runtime.SetBlockProfileRate(conf.BlockProfileRate)
+	} else if conf.BlockProfile != "" {
+		runtime.SetBlockProfileRate(1)
+	}
+	if conf.MutexProfileFraction != 0 {
+		runtime.SetMutexProfileFraction(conf.MutexProfileFraction)
+	} else if conf.MutexProfile != "" {
+		runtime.SetMutexProfileFraction(1)
+	}
+}
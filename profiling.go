@@ -0,0 +1,66 @@
+package ginkgo
+
+import (
+	"os"
+	"runtime/pprof"
+
+	"github.com/onsi/ginkgo/config"
+)
+
+// startCPUProfile begins a CPU profile at conf.CPUProfile, if one was
+// requested. It returns a function that stops the profile and closes the
+// file; callers should defer the returned function around suite execution.
+func startCPUProfile(conf config.GinkgoConfigType) (func(), error) {
+	if conf.CPUProfile == "" {
+		return func() {}, nil
+	}
+
+	f, err := os.Create(conf.CPUProfile)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := pprof.StartCPUProfile(f); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return func() {
+		pprof.StopCPUProfile()
+		f.Close()
+	}, nil
+}
+
+// writeLookupProfile writes the named runtime/pprof profile (e.g. "heap",
+// "block", "mutex") to path.
+func writeLookupProfile(name string, path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return pprof.Lookup(name).WriteTo(f, 0)
+}
+
+// writeEndOfSuiteProfiles writes the memory/block/mutex profiles requested
+// in conf. Unlike the CPU profile, these are snapshots taken once at suite
+// end rather than started/stopped around execution.
+func writeEndOfSuiteProfiles(conf config.GinkgoConfigType) error {
+	if conf.MemProfile != "" {
+		if err := writeLookupProfile("heap", conf.MemProfile); err != nil {
+			return err
+		}
+	}
+	if conf.BlockProfile != "" {
+		if err := writeLookupProfile("block", conf.BlockProfile); err != nil {
+			return err
+		}
+	}
+	if conf.MutexProfile != "" {
+		if err := writeLookupProfile("mutex", conf.MutexProfile); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package ginkgo
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/onsi/ginkgo/config"
+	"github.com/onsi/ginkgo/internal/specrunner"
+)
+
+// RunSpecs walks the specs registered via Describe/Context/It against the
+// suite tree built while this binary's _test.go files were loaded, running
+// each one in turn. It returns whether every spec passed.
+func RunSpecs(t GinkgoTestingT, description string) bool {
+	fs := flag.NewFlagSet("ginkgo", flag.ContinueOnError)
+	config.Flags(fs, &config.GinkgoConfig)
+	fs.Parse(os.Args[1:])
+
+	conf := config.GinkgoConfig
+
+	applyProfileRates(conf)
+
+	goroutineProfilePath := conf.GoroutineProfile
+	if goroutineProfilePath == "" && conf.DumpGoroutinesOnTimeout {
+		goroutineProfilePath = "goroutines.out"
+	}
+
+	timeoutCh := make(chan struct{})
+	if conf.DumpGoroutinesOnTimeout {
+		stopDumpHandler := installDumpGoroutinesOnTimeoutHandler(goroutineProfilePath, stackDumpPath(goroutineProfilePath), timeoutCh)
+		defer stopDumpHandler()
+	}
+
+	stopTrace, err := startTrace(conf)
+	if err != nil {
+		fmt.Fprintf(GinkgoWriter, "failed to start execution trace: %s\n", err)
+	}
+
+	stopCPUProfile, err := startCPUProfile(conf)
+	if err != nil {
+		fmt.Fprintf(GinkgoWriter, "failed to start CPU profile: %s\n", err)
+	}
+
+	specs := collectSpecs(globalSuite.root, nil, nil, nil)
+
+	passed, failed := 0, 0
+	for _, s := range specs {
+		if runSpecWithTimeout(s, conf, goroutineProfilePath, timeoutCh) {
+			passed++
+		} else {
+			failed++
+		}
+	}
+
+	stopCPUProfile()
+	stopTrace()
+	if err := writeEndOfSuiteProfiles(conf); err != nil {
+		fmt.Fprintf(GinkgoWriter, "failed to write end-of-suite profiles: %s\n", err)
+	}
+	if conf.GoroutineProfile != "" {
+		if err := writeGoroutineProfile(conf.GoroutineProfile); err != nil {
+			fmt.Fprintf(GinkgoWriter, "failed to write goroutine profile: %s\n", err)
+		}
+	}
+
+	fmt.Fprintf(GinkgoWriter, "Ran %d of %d Specs in %s\n", passed+failed, passed+failed, description)
+
+	success := failed == 0
+	if !success {
+		t.Fail()
+	}
+	return success
+}
+
+// runSpecWithTimeout runs s, enforcing conf.Timeout if one is set. A spec
+// still running when its timeout elapses is, by definition, a goroutine
+// that can't safely be cancelled, so - after giving the SIGQUIT/timeout
+// dump handler a chance to run, when --dump-goroutines-on-timeout is set -
+// it exits the whole suite process rather than returning.
+func runSpecWithTimeout(s spec, conf config.GinkgoConfigType, goroutineProfilePath string, timeoutCh chan struct{}) bool {
+	if conf.Timeout <= 0 {
+		return runSpec(s, conf)
+	}
+
+	done := make(chan bool, 1)
+	go func() {
+		done <- runSpec(s, conf)
+	}()
+
+	select {
+	case passed := <-done:
+		return passed
+	case <-time.After(conf.Timeout):
+		fmt.Fprintf(GinkgoWriter, "Timed out after %s waiting for spec %q\n", conf.Timeout, s.fullText)
+		if conf.DumpGoroutinesOnTimeout {
+			close(timeoutCh)
+			time.Sleep(100 * time.Millisecond)
+		}
+		os.Exit(1)
+		return false
+	}
+}
+
+// runSpec runs a single spec's BeforeEach/It/AfterEach chain, recovering
+// from the panic that Fail uses to unwind out of a failing spec. Each
+// BeforeEach, the It, and each AfterEach run inside
+// specrunner.RunLeafNodeWithLabels so that CPU/block/mutex samples taken
+// during them are tagged with the spec that produced them; the hooks carry
+// the spec's text and the It's code location.
+func runSpec(s spec, conf config.GinkgoConfigType) (passed bool) {
+	passed = true
+
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				passed = false
+				reportSpecFailure(s, r)
+			}
+		}()
+
+		for _, before := range s.befores {
+			specrunner.RunLeafNodeWithLabels(context.Background(), conf.ProfileLabels, s.fullText, s.codeLocation, "BeforeEach", func(context.Context) {
+				before()
+			})
+		}
+		specrunner.RunLeafNodeWithLabels(context.Background(), conf.ProfileLabels, s.fullText, s.codeLocation, "It", func(context.Context) {
+			s.body()
+		})
+	}()
+
+	for _, after := range s.afters {
+		specrunner.RunLeafNodeWithLabels(context.Background(), conf.ProfileLabels, s.fullText, s.codeLocation, "AfterEach", func(context.Context) {
+			after()
+		})
+	}
+
+	return passed
+}
+
+// reportSpecFailure prints the failure message and location for a spec that
+// panicked, whether via Fail (specFailure) or an unrecovered runtime panic.
+func reportSpecFailure(s spec, r interface{}) {
+	message := fmt.Sprintf("%v", r)
+	if f, ok := r.(specFailure); ok {
+		message = f.message
+	}
+	fmt.Fprintf(GinkgoWriter, "\n[FAILED] %s\n%s\n%s\n", s.fullText, message, s.codeLocation)
+}
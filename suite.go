@@ -0,0 +1,196 @@
+package ginkgo
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+)
+
+type nodeType int
+
+const (
+	nodeTypeContainer nodeType = iota
+	nodeTypeIt
+	nodeTypeBeforeEach
+	nodeTypeAfterEach
+)
+
+type node struct {
+	nodeType     nodeType
+	text         string
+	codeLocation string
+	body         func()
+	children     []*node
+}
+
+// callerLocation returns "file:line" for the caller of the Describe/Context/
+// It/BeforeEach/AfterEach that invoked it, for tagging profile samples.
+func callerLocation(skip int) string {
+	_, file, line, ok := runtime.Caller(skip + 1)
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("%s:%d", file, line)
+}
+
+// suiteTree is the tree of containers/leaf-nodes built up by Describe/
+// Context/It/BeforeEach/AfterEach as the suite's _test.go files are
+// evaluated at package init time.
+type suiteTree struct {
+	root  *node
+	stack []*node
+}
+
+var globalSuite = newSuiteTree()
+
+func newSuiteTree() *suiteTree {
+	root := &node{nodeType: nodeTypeContainer, text: "[root]"}
+	return &suiteTree{root: root, stack: []*node{root}}
+}
+
+func (s *suiteTree) current() *node {
+	return s.stack[len(s.stack)-1]
+}
+
+func (s *suiteTree) pushContainer(text string, body func()) {
+	n := &node{nodeType: nodeTypeContainer, text: text}
+	parent := s.current()
+	parent.children = append(parent.children, n)
+
+	s.stack = append(s.stack, n)
+	body()
+	s.stack = s.stack[:len(s.stack)-1]
+}
+
+func (s *suiteTree) appendLeaf(nodeType nodeType, text string, codeLocation string, body func()) {
+	n := &node{nodeType: nodeType, text: text, codeLocation: codeLocation, body: body}
+	parent := s.current()
+	parent.children = append(parent.children, n)
+}
+
+// Describe registers a named container of specs and hooks. body is invoked
+// synchronously to register its children before Describe returns.
+func Describe(text string, body func()) bool {
+	globalSuite.pushContainer(text, body)
+	return true
+}
+
+// Context is an alias for Describe used for stylistic nesting.
+var Context = Describe
+
+// It registers a single spec.
+func It(text string, body func()) bool {
+	globalSuite.appendLeaf(nodeTypeIt, text, callerLocation(1), body)
+	return true
+}
+
+// BeforeEach registers a hook that runs before every spec nested under the
+// enclosing container.
+func BeforeEach(body func()) bool {
+	globalSuite.appendLeaf(nodeTypeBeforeEach, "", "", body)
+	return true
+}
+
+// AfterEach registers a hook that runs after every spec nested under the
+// enclosing container, even if the spec failed.
+func AfterEach(body func()) bool {
+	globalSuite.appendLeaf(nodeTypeAfterEach, "", "", body)
+	return true
+}
+
+// By annotates the currently running spec with a step description.
+func By(text string) {
+	fmt.Fprintln(GinkgoWriter, text)
+}
+
+// spec is a single, fully resolved It together with the BeforeEach/AfterEach
+// hooks that apply to it, collected by walking the container tree.
+type spec struct {
+	fullText     string
+	codeLocation string
+	befores      []func()
+	body         func()
+	afters       []func()
+}
+
+// collectSpecs flattens the container tree rooted at n into the list of
+// specs that should run, threading each container's BeforeEach/AfterEach
+// hooks onto every spec nested beneath it.
+func collectSpecs(n *node, textPath []string, befores []func(), afters []func()) []spec {
+	var localBefores, localAfters []func()
+	for _, c := range n.children {
+		switch c.nodeType {
+		case nodeTypeBeforeEach:
+			localBefores = append(localBefores, c.body)
+		case nodeTypeAfterEach:
+			localAfters = append(localAfters, c.body)
+		}
+	}
+
+	combinedBefores := append(append([]func(){}, befores...), localBefores...)
+	// AfterEach hooks run innermost-first, so this container's afters run
+	// before the ones it inherited from its parents.
+	combinedAfters := append(append([]func(){}, localAfters...), afters...)
+
+	var specs []spec
+	for _, c := range n.children {
+		switch c.nodeType {
+		case nodeTypeContainer:
+			specs = append(specs, collectSpecs(c, append(textPath, c.text), combinedBefores, combinedAfters)...)
+		case nodeTypeIt:
+			specs = append(specs, spec{
+				fullText:     joinText(append(textPath, c.text)),
+				codeLocation: c.codeLocation,
+				befores:      combinedBefores,
+				body:         c.body,
+				afters:       combinedAfters,
+			})
+		}
+	}
+	return specs
+}
+
+func joinText(parts []string) string {
+	out := ""
+	for i, p := range parts {
+		if i > 0 {
+			out += " "
+		}
+		out += p
+	}
+	return out
+}
+
+// GinkgoTestingT is the subset of *testing.T that RunSpecs needs.
+type GinkgoTestingT interface {
+	Fail()
+}
+
+// specFailure is panicked by Fail to unwind out of a running spec.
+type specFailure struct {
+	message string
+}
+
+// Fail marks the currently running spec as failed and stops its execution.
+// callerSkip is accepted (and ignored) for compatibility with the way
+// gomega's RegisterFailHandler calls it.
+func Fail(message string, callerSkip ...int) {
+	panic(specFailure{message: message})
+}
+
+type ginkgoWriter struct {
+	w io.Writer
+}
+
+func (g *ginkgoWriter) Write(p []byte) (int, error) {
+	return g.w.Write(p)
+}
+
+func (g *ginkgoWriter) Printf(format string, args ...interface{}) {
+	fmt.Fprintf(g.w, format, args...)
+}
+
+// GinkgoWriter is where specs and Ginkgo itself should write diagnostic
+// output; it is always printed, regardless of whether a spec passes.
+var GinkgoWriter = &ginkgoWriter{w: os.Stdout}
@@ -0,0 +1,33 @@
+package ginkgo
+
+import (
+	"os"
+	"runtime/trace"
+
+	"github.com/onsi/ginkgo/config"
+)
+
+// startTrace begins a runtime/trace execution trace at config.GinkgoConfig.Trace,
+// if one was requested, analogous to how RunSpecs threads --cpuprofile today.
+// It returns a function that stops the trace and closes the file; callers
+// should defer the returned function around suite execution.
+func startTrace(conf config.GinkgoConfigType) (func(), error) {
+	if conf.Trace == "" {
+		return func() {}, nil
+	}
+
+	f, err := os.Create(conf.Trace)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := trace.Start(f); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return func() {
+		trace.Stop()
+		f.Close()
+	}, nil
+}